@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/charmbracelet/log"
+	"go.abhg.dev/gs/internal/git"
+)
+
+// checkoutOptions holds flags shared by commands that end by checking
+// out a branch (trunkCmd, branchCheckoutCmd, and the downstack edit
+// flow).
+type checkoutOptions struct{}
+
+type branchCheckoutCmd struct {
+	checkoutOptions
+
+	Branch string `arg:"" optional:"" help:"Name of the branch to check out."`
+}
+
+func (cmd *branchCheckoutCmd) Run(ctx context.Context, log *log.Logger, opts *globalOptions) error {
+	repo, err := git.Open(ctx, ".", git.OpenOptions{
+		Log: log,
+	})
+	if err != nil {
+		return fmt.Errorf("open repository: %w", err)
+	}
+
+	store, err := ensureStore(ctx, repo, log, opts)
+	if err != nil {
+		return err
+	}
+
+	if err := requireNoRestackInProgress(ctx, store); err != nil {
+		return err
+	}
+
+	if err := repo.Checkout(ctx, cmd.Branch); err != nil {
+		return fmt.Errorf("checkout branch %v: %w", cmd.Branch, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package main
+
+// cli is the root Kong command grammar for gs. Each field maps to a
+// subcommand (or a group of them); the corresponding *Cmd type's Run
+// method is invoked with whichever of ctx/log/opts it asks for.
+var cli struct {
+	Trunk trunkCmd `cmd:"" help:"Check out the trunk branch."`
+
+	Branch struct {
+		Checkout branchCheckoutCmd `cmd:"" help:"Check out a branch tracked by gs."`
+	} `cmd:"" help:"Manage branches tracked by gs."`
+
+	Upstack struct {
+		Restack upstackRestackCmd `cmd:"" help:"Restack the current branch and all its upstacks."`
+	} `cmd:"" help:"Commands that operate on the upstack of the current branch."`
+
+	Downstack struct {
+		Edit downstackEditCmd `cmd:"" help:"Reorder, drop, squash, or rename branches below the current one."`
+	} `cmd:"" help:"Commands that operate on the downstack of the current branch."`
+
+	Completion completionCmd `cmd:"" help:"Generate a shell completion script."`
+
+	Complete struct {
+		Branches completeBranchesCmd `cmd:"" help:"List branch names for shell completion."`
+	} `cmd:"" hidden:"" name:"__complete" help:"Internal helpers used by shell completion scripts."`
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/charmbracelet/log"
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/gs"
+)
+
+// completionCmd emits a shell completion script for the given shell.
+// The generated script calls the hidden "gs __complete branches <kind>"
+// command to offer branch names known to the store, rather than raw
+// Git refs, so e.g. `gs branch onto <TAB>` only offers the current
+// branch and the branches below it.
+type completionCmd struct {
+	Shell string `arg:"" enum:"bash,zsh,fish,powershell" help:"Shell to generate a completion script for."`
+}
+
+func (cmd *completionCmd) Run() error {
+	script, ok := _completionScripts[cmd.Shell]
+	if !ok {
+		// Unreachable: cmd.Shell is already validated by the enum tag.
+		return fmt.Errorf("unsupported shell %q", cmd.Shell)
+	}
+	fmt.Print(script)
+	return nil
+}
+
+// _completionScripts holds the hand-written completion script for each
+// supported shell. Each script walks the words on the command line,
+// looks up which gs command is being completed, and shells out to
+// `gs __complete branches <kind>` for the branch candidates.
+var _completionScripts = map[string]string{
+	"bash":       _bashCompletionScript,
+	"zsh":        _zshCompletionScript,
+	"fish":       _fishCompletionScript,
+	"powershell": _powershellCompletionScript,
+}
+
+const _bashCompletionScript = `# gs bash completion
+_gs_complete_branches() {
+    local kind=$1
+    COMPREPLY=($(compgen -W "$(gs __complete branches "$kind" 2>/dev/null)" -- "$cur"))
+}
+
+_gs() {
+    local cur words cword
+    _init_completion || return
+    words="${COMP_WORDS[*]:1:$((cword-1))}"
+
+    case "$words" in
+        "branch onto"*)     _gs_complete_branches downstack ;;
+        "upstack restack"*) _gs_complete_branches upstack ;;
+        "branch checkout"*) _gs_complete_branches all ;;
+        "trunk"*)           _gs_complete_branches all ;;
+        *) COMPREPLY=() ;;
+    esac
+}
+
+complete -F _gs gs
+`
+
+const _zshCompletionScript = `#compdef gs
+# gs zsh completion
+
+_gs_complete_branches() {
+    local -a branches
+    branches=("${(@f)$(gs __complete branches "$1" 2>/dev/null)}")
+    _describe 'branch' branches
+}
+
+_gs() {
+    local context state line
+    local words_so_far="${words[2,-2]}"
+
+    case "$words_so_far" in
+        "branch onto"*)     _gs_complete_branches downstack ;;
+        "upstack restack"*) _gs_complete_branches upstack ;;
+        "branch checkout"*) _gs_complete_branches all ;;
+        "trunk"*)           _gs_complete_branches all ;;
+    esac
+}
+
+compdef _gs gs
+`
+
+const _fishCompletionScript = `# gs fish completion
+function __gs_complete_branches
+    gs __complete branches $argv[1] 2>/dev/null
+end
+
+complete -c gs -n '__fish_seen_subcommand_from branch; and __fish_seen_subcommand_from onto' \
+    -f -a '(__gs_complete_branches downstack)'
+complete -c gs -n '__fish_seen_subcommand_from upstack; and __fish_seen_subcommand_from restack' \
+    -f -a '(__gs_complete_branches upstack)'
+complete -c gs -n '__fish_seen_subcommand_from branch; and __fish_seen_subcommand_from checkout' \
+    -f -a '(__gs_complete_branches all)'
+complete -c gs -n '__fish_seen_subcommand_from trunk' \
+    -f -a '(__gs_complete_branches all)'
+`
+
+const _powershellCompletionScript = `# gs powershell completion
+Register-ArgumentCompleter -Native -CommandName gs -ScriptBlock {
+    param($wordToComplete, $commandAst, $cursorPosition)
+
+    $line = $commandAst.ToString()
+    $kind = switch -Regex ($line) {
+        'branch\s+onto'     { 'downstack'; break }
+        'upstack\s+restack' { 'upstack'; break }
+        'branch\s+checkout' { 'all'; break }
+        'trunk'             { 'all'; break }
+        default             { $null }
+    }
+
+    if ($kind) {
+        gs __complete branches $kind | Where-Object { $_ -like "$wordToComplete*" } |
+            ForEach-Object { [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_) }
+    }
+}
+`
+
+// completeBranchesKind selects which subset of tracked branches
+// __complete branches should offer, matching the command it's
+// completing arguments for.
+type completeBranchesKind string
+
+const (
+	_completeDownstack completeBranchesKind = "downstack"
+	_completeUpstack   completeBranchesKind = "upstack"
+	_completeAll       completeBranchesKind = "all"
+)
+
+// completeBranchesCmd is a hidden helper invoked by the shell completion
+// scripts generated by completionCmd. It prints one tracked branch name
+// per line, using the same gs.Service calls the commands it completes
+// for already use, so that e.g. `gs branch onto <TAB>` only offers
+// the current branch and the branches below it.
+type completeBranchesCmd struct {
+	Kind string `arg:"" optional:"" enum:"downstack,upstack,all" default:"all" help:"Which branches to list."`
+}
+
+func (cmd *completeBranchesCmd) Run(ctx context.Context, log *log.Logger, opts *globalOptions) error {
+	repo, err := git.Open(ctx, ".", git.OpenOptions{
+		Log: log,
+	})
+	if err != nil {
+		return fmt.Errorf("open repository: %w", err)
+	}
+
+	store, err := ensureStore(ctx, repo, log, opts)
+	if err != nil {
+		return err
+	}
+
+	svc := gs.NewService(repo, store, log)
+
+	currentBranch, err := repo.CurrentBranch(ctx)
+	if err != nil {
+		return fmt.Errorf("get current branch: %w", err)
+	}
+
+	var branches []string
+	switch completeBranchesKind(cmd.Kind) {
+	case _completeDownstack:
+		branches, err = svc.ListDownstack(ctx, currentBranch)
+	case _completeUpstack:
+		branches, err = svc.ListUpstack(ctx, currentBranch)
+	default:
+		branches, err = svc.AllBranches(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("list branches: %w", err)
+	}
+
+	for _, branch := range branches {
+		fmt.Println(branch)
+	}
+
+	return nil
+}
@@ -10,21 +10,25 @@ import (
 	"os"
 	"os/exec"
 	"slices"
+	"strings"
 
 	"github.com/charmbracelet/log"
 	"go.abhg.dev/gs/internal/git"
 	"go.abhg.dev/gs/internal/gs"
 	"go.abhg.dev/gs/internal/must"
+	"golang.org/x/term"
 )
 
 type downstackEditCmd struct {
-	Editor string `env:"EDITOR" help:"Editor to use for editing the downstack."`
+	Editor      string `env:"EDITOR" help:"Editor to use for editing the downstack."`
+	Interactive bool   `short:"i" help:"Use an interactive TUI to reorder the downstack instead of an editor."`
 
 	Name string `arg:"" optional:"" help:"Name of the branch to start editing from."`
 }
 
 func (cmd *downstackEditCmd) Run(ctx context.Context, log *log.Logger, opts *globalOptions) error {
-	if cmd.Editor == "" {
+	useTUI := cmd.Interactive || (cmd.Editor == "" && term.IsTerminal(int(os.Stdout.Fd())))
+	if !useTUI && cmd.Editor == "" {
 		return errors.New("an editor is required: use --editor or set $EDITOR")
 	}
 
@@ -72,9 +76,118 @@ func (cmd *downstackEditCmd) Run(ctx context.Context, log *log.Logger, opts *glo
 		originalBranches[branch] = struct{}{}
 	}
 
+	var newOrder []string
+	if useTUI {
+		newOrder, err = runDownstackEditTUI(ctx, repo, svc, store, downstacks)
+		if err != nil {
+			return fmt.Errorf("run interactive editor: %w", err)
+		}
+	} else {
+		newOrder, err = cmd.runEditor(ctx, svc, downstacks, originalBranches)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(newOrder) == 0 {
+		log.Infof("downstack edit aborted or nothing to do")
+		return nil
+	}
+	newTop := newOrder[0]
+	slices.Reverse(newOrder)
+
+	base := store.Trunk()
+	for _, branch := range newOrder {
+		err := (&branchOntoCmd{
+			Branch: branch,
+			Onto:   base,
+		}).Run(ctx, log, opts)
+		if err != nil {
+			return fmt.Errorf("branch onto %s: %w", branch, err)
+		}
+		base = branch
+	}
+
+	return (&branchCheckoutCmd{
+		Branch: newTop,
+	}).Run(ctx, log, opts)
+}
+
+// editVerb is one of the per-line verbs recognized in the downstack edit
+// instruction file, modeled after `git rebase -i`'s todo list.
+type editVerb string
+
+const (
+	editVerbPick   editVerb = "pick"
+	editVerbDrop   editVerb = "drop"
+	editVerbSquash editVerb = "squash"
+	editVerbRename editVerb = "rename"
+)
+
+// editLine is a single parsed, non-comment line of the instruction file.
+type editLine struct {
+	Verb      editVerb
+	Branch    string
+	NewBranch string // only set for editVerbRename
+}
+
+// parseEditLine tokenizes a single line of the instruction file into a
+// verb and its arguments. A bare branch name with no verb is treated as
+// "pick <branch>", preserving the original file format.
+func parseEditLine(line string) (editLine, error) {
+	fields := strings.Fields(line)
+
+	// A bare branch name with no verb at all is "pick <branch>". A lone
+	// token that happens to spell one of the verb names (e.g. a "drop"
+	// line missing its branch) is almost certainly a mistyped verb
+	// line, not a branch actually named "drop" — treat it as that verb
+	// with no arguments so it hits the "expected ..." errors below
+	// instead of silently becoming "pick drop".
+	verb := editVerbPick
+	args := fields
+	if len(fields) > 0 {
+		switch editVerb(fields[0]) {
+		case editVerbPick, editVerbDrop, editVerbSquash, editVerbRename:
+			verb = editVerb(fields[0])
+			args = fields[1:]
+		default:
+			if len(fields) > 1 {
+				return editLine{}, fmt.Errorf("unknown verb %q", fields[0])
+			}
+		}
+	}
+
+	switch verb {
+	case editVerbPick, editVerbDrop, editVerbSquash:
+		if len(args) != 1 {
+			return editLine{}, fmt.Errorf("%v: expected exactly one branch name", verb)
+		}
+		return editLine{Verb: verb, Branch: args[0]}, nil
+
+	case editVerbRename:
+		if len(args) != 2 {
+			return editLine{}, fmt.Errorf("rename: expected '%s <branch> <new-name>'", editVerbRename)
+		}
+		return editLine{Verb: verb, Branch: args[0], NewBranch: args[1]}, nil
+
+	default:
+		return editLine{}, fmt.Errorf("unknown verb %q", fields[0])
+	}
+}
+
+// runEditor drives the original `$EDITOR`-based workflow:
+// it writes the downstack to a temporary file, opens it in cmd.Editor,
+// and parses the result back into an ordered list of branches, applying
+// drop/squash/rename verbs against svc along the way.
+func (cmd *downstackEditCmd) runEditor(
+	ctx context.Context,
+	svc *gs.Service,
+	downstacks []string,
+	originalBranches map[string]struct{},
+) ([]string, error) {
 	instructionFile, err := createEditFile(downstacks)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	editCmd := exec.CommandContext(ctx, cmd.Editor, instructionFile)
@@ -82,15 +195,15 @@ func (cmd *downstackEditCmd) Run(ctx context.Context, log *log.Logger, opts *glo
 	editCmd.Stdout = os.Stdout
 	editCmd.Stderr = os.Stderr
 	if err := editCmd.Run(); err != nil {
-		return fmt.Errorf("run editor: %w", err)
+		return nil, fmt.Errorf("run editor: %w", err)
 	}
 
 	f, err := os.Open(instructionFile)
 	if err != nil {
-		return fmt.Errorf("open edited file: %w", err)
+		return nil, fmt.Errorf("open edited file: %w", err)
 	}
 
-	newOrder := make([]string, 0, len(downstacks))
+	var lines []editLine
 	scanner := bufio.NewScanner(f)
 	for scanner.Scan() {
 		bs := bytes.TrimSpace(scanner.Bytes())
@@ -98,42 +211,82 @@ func (cmd *downstackEditCmd) Run(ctx context.Context, log *log.Logger, opts *glo
 			continue
 		}
 
-		name := string(bs)
-		if _, ok := originalBranches[name]; !ok {
+		line, err := parseEditLine(string(bs))
+		if err != nil {
+			return nil, fmt.Errorf("parse line %q: %w", bs, err)
+		}
+
+		if _, ok := originalBranches[line.Branch]; !ok {
 			// TODO: better error
-			return fmt.Errorf("branch %q not in original downstack, or is duplicated", name)
+			return nil, fmt.Errorf("branch %q not in original downstack, or is duplicated", line.Branch)
 		}
-		delete(originalBranches, name)
+		delete(originalBranches, line.Branch)
 
-		newOrder = append(newOrder, name)
+		lines = append(lines, line)
 	}
 
 	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("read edited file: %w", err)
+		return nil, fmt.Errorf("read edited file: %w", err)
 	}
 
-	if len(newOrder) == 0 {
-		log.Infof("downstack edit aborted or nothing to do")
-		return nil
+	return cmd.applyEditLines(ctx, svc, lines)
+}
+
+// applyEditLines performs the drop, squash, and rename verbs against svc
+// and returns the resulting branch order (top to bottom) for the
+// branchOntoCmd loop in Run.
+func (cmd *downstackEditCmd) applyEditLines(ctx context.Context, svc *gs.Service, lines []editLine) ([]string, error) {
+	// Drops are independent of ordering: remove the branch and restack
+	// its upstacks (within the downstack) onto its base immediately.
+	kept := lines[:0:0] //nolint:staticcheck // intentional fresh backing array
+	for _, line := range lines {
+		if line.Verb != editVerbDrop {
+			kept = append(kept, line)
+			continue
+		}
+
+		if err := svc.DeleteBranch(ctx, line.Branch, gs.DeleteBranchOptions{
+			Restack: true,
+		}); err != nil {
+			return nil, fmt.Errorf("drop %s: %w", line.Branch, err)
+		}
 	}
-	newTop := newOrder[0]
-	slices.Reverse(newOrder)
+	lines = kept
 
-	base := store.Trunk()
-	for _, branch := range newOrder {
-		err := (&branchOntoCmd{
-			Branch: branch,
-			Onto:   base,
-		}).Run(ctx, log, opts)
-		if err != nil {
-			return fmt.Errorf("branch onto %s: %w", branch, err)
+	// Squash merges a branch's commits into the branch below it (the
+	// next surviving entry, i.e. closer to trunk) and removes it from
+	// the list.
+	kept = lines[:0:0]
+	for i, line := range lines {
+		if line.Verb != editVerbSquash {
+			kept = append(kept, line)
+			continue
+		}
+
+		if i+1 >= len(lines) {
+			return nil, fmt.Errorf("squash %s: no branch below it to squash into", line.Branch)
+		}
+		below := lines[i+1].Branch
+
+		if err := svc.SquashBranch(ctx, line.Branch, below); err != nil {
+			return nil, fmt.Errorf("squash %s into %s: %w", line.Branch, below, err)
 		}
-		base = branch
 	}
+	lines = kept
 
-	return (&branchCheckoutCmd{
-		Name: newTop,
-	}).Run(ctx, log, opts)
+	newOrder := make([]string, 0, len(lines))
+	for _, line := range lines {
+		name := line.Branch
+		if line.Verb == editVerbRename {
+			if err := svc.RenameBranch(ctx, line.Branch, line.NewBranch); err != nil {
+				return nil, fmt.Errorf("rename %s to %s: %w", line.Branch, line.NewBranch, err)
+			}
+			name = line.NewBranch
+		}
+		newOrder = append(newOrder, name)
+	}
+
+	return newOrder, nil
 }
 
 var _editFooter = `
@@ -142,6 +295,12 @@ var _editFooter = `
 # Branches above that will be stacked on top of it in the order they appear.
 # Branches deleted from the list will not be modified.
 #
+# Lines may optionally start with a verb:
+#   pick <branch>                use the branch (default if no verb is given)
+#   drop <branch>                remove the branch and restack its upstacks onto its base
+#   squash <branch>              meld the branch's commits into the branch below it
+#   rename <branch> <new-name>   rename the branch as part of the reorder
+#
 # Save and quit the editor to apply the changes.
 # Delete all lines in the editor to abort the operation.
 `
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseEditLine(t *testing.T) {
+	tests := []struct {
+		name       string
+		line       string
+		want       editLine
+		wantErrStr string
+	}{
+		{
+			name: "bare branch name is pick",
+			line: "feature",
+			want: editLine{Verb: editVerbPick, Branch: "feature"},
+		},
+		{
+			name: "explicit pick",
+			line: "pick feature",
+			want: editLine{Verb: editVerbPick, Branch: "feature"},
+		},
+		{
+			name: "drop",
+			line: "drop feature",
+			want: editLine{Verb: editVerbDrop, Branch: "feature"},
+		},
+		{
+			name: "squash",
+			line: "squash feature",
+			want: editLine{Verb: editVerbSquash, Branch: "feature"},
+		},
+		{
+			name: "rename",
+			line: "rename feature new-feature",
+			want: editLine{Verb: editVerbRename, Branch: "feature", NewBranch: "new-feature"},
+		},
+		{
+			name:       "unknown verb",
+			line:       "foo feature",
+			wantErrStr: `unknown verb "foo"`,
+		},
+		{
+			name:       "rename missing new name",
+			line:       "rename feature",
+			wantErrStr: "rename: expected",
+		},
+		{
+			name:       "lone verb token missing its branch",
+			line:       "drop",
+			wantErrStr: "drop: expected exactly one branch name",
+		},
+		{
+			name: "lone unknown token is treated as a branch name",
+			line: "mistyped-branch",
+			want: editLine{Verb: editVerbPick, Branch: "mistyped-branch"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseEditLine(tt.line)
+			if tt.wantErrStr != "" {
+				if err == nil {
+					t.Fatalf("parseEditLine(%q) = %+v, want error containing %q", tt.line, got, tt.wantErrStr)
+				}
+				if !strings.Contains(err.Error(), tt.wantErrStr) {
+					t.Fatalf("parseEditLine(%q) error = %q, want substring %q", tt.line, err.Error(), tt.wantErrStr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("parseEditLine(%q) unexpected error: %v", tt.line, err)
+			}
+			if got != tt.want {
+				t.Fatalf("parseEditLine(%q) = %+v, want %+v", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
@@ -0,0 +1,354 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/gs"
+)
+
+// runDownstackEditTUI presents a full-screen, keyboard-driven editor for
+// reordering the given downstack branches.
+//
+// It returns the same kind of newOrder slice that the $EDITOR-based flow
+// produces: branches in top-to-bottom display order (the branch closest
+// to the current one first), with deleted branches omitted. downstackEditCmd.Run
+// reverses this before feeding it, bottom-up, into the branchOntoCmd
+// loop. A nil slice means the user aborted the operation.
+func runDownstackEditTUI(ctx context.Context, repo *git.Repository, svc *gs.Service, store *gs.Store, branches []string) ([]string, error) {
+	m := newDownstackEditModel(ctx, repo, svc, store, branches)
+
+	p := tea.NewProgram(m, tea.WithContext(ctx))
+	final, err := p.Run()
+	if err != nil {
+		return nil, fmt.Errorf("run TUI: %w", err)
+	}
+
+	result := final.(*downstackEditModel)
+	if !result.applied {
+		return nil, nil
+	}
+
+	newOrder := make([]string, len(result.items))
+	copy(newOrder, result.items)
+	return newOrder, nil
+}
+
+var (
+	_selectedItemStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12"))
+	_helpStyle         = lipgloss.NewStyle().Foreground(lipgloss.Color("243"))
+	_previewStyle      = lipgloss.NewStyle().Border(lipgloss.NormalBorder()).Padding(0, 1)
+)
+
+type downstackEditKeyMap struct {
+	Up     key.Binding
+	Down   key.Binding
+	MoveUp key.Binding
+	MoveDn key.Binding
+	Delete key.Binding
+	Insert key.Binding
+	Cancel key.Binding
+	Quit   key.Binding
+	Apply  key.Binding
+}
+
+var _downstackEditKeys = downstackEditKeyMap{
+	Up:     key.NewBinding(key.WithKeys("up", "k")),
+	Down:   key.NewBinding(key.WithKeys("down", "j")),
+	MoveUp: key.NewBinding(key.WithKeys("K", "shift+up")),
+	MoveDn: key.NewBinding(key.WithKeys("J", "shift+down")),
+	Delete: key.NewBinding(key.WithKeys("d")),
+	Insert: key.NewBinding(key.WithKeys("i")),
+	Cancel: key.NewBinding(key.WithKeys("esc")),
+	Quit:   key.NewBinding(key.WithKeys("q", "ctrl+c")),
+	Apply:  key.NewBinding(key.WithKeys("enter")),
+}
+
+// downstackEditModel is a bubbletea model backing the interactive
+// `downstack edit` TUI. It lives behind the same orchestration as the
+// $EDITOR-based flow: it only ever decides on a []string of branch
+// names, which downstackEditCmd.Run then feeds into branchOntoCmd.
+type downstackEditModel struct {
+	ctx   context.Context
+	repo  *git.Repository
+	svc   *gs.Service
+	store *gs.Store
+
+	// items holds the branches in top-to-bottom display order.
+	items    []string
+	cursor   int
+	applied  bool
+	inserted map[string]struct{} // branches pulled in from outside the original downstack
+
+	// inserting is non-nil while the "insert a branch" picker is open,
+	// holding the branches available to insert and the picker's own
+	// cursor.
+	inserting *insertPicker
+
+	viewport viewport.Model
+	width    int
+	height   int
+}
+
+// insertPicker is the state of the "i" (insert branch) sub-view: a list
+// of branches from the wider stack that aren't already in the edit
+// list, for the user to choose from.
+type insertPicker struct {
+	candidates []string
+	cursor     int
+}
+
+func newDownstackEditModel(ctx context.Context, repo *git.Repository, svc *gs.Service, store *gs.Store, branches []string) *downstackEditModel {
+	items := make([]string, len(branches))
+	copy(items, branches)
+
+	return &downstackEditModel{
+		ctx:      ctx,
+		repo:     repo,
+		svc:      svc,
+		store:    store,
+		items:    items,
+		inserted: make(map[string]struct{}),
+		viewport: viewport.New(40, 10),
+	}
+}
+
+func (m *downstackEditModel) Init() tea.Cmd {
+	return m.loadPreviewCmd()
+}
+
+func (m *downstackEditModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.viewport.Width = msg.Width / 2
+		m.viewport.Height = msg.Height - 4
+		return m, nil
+
+	case previewLoadedMsg:
+		m.viewport.SetContent(msg.text)
+		return m, nil
+
+	case insertCandidatesMsg:
+		if len(msg.candidates) == 0 {
+			return m, nil
+		}
+		m.inserting = &insertPicker{candidates: msg.candidates}
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.inserting != nil {
+			return m.updateInserting(msg)
+		}
+		return m.updateNormal(msg)
+	}
+
+	return m, nil
+}
+
+func (m *downstackEditModel) updateNormal(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, _downstackEditKeys.Quit):
+		return m, tea.Quit
+
+	case key.Matches(msg, _downstackEditKeys.Apply):
+		m.applied = true
+		return m, tea.Quit
+
+	case key.Matches(msg, _downstackEditKeys.Up):
+		if m.cursor > 0 {
+			m.cursor--
+		}
+		return m, m.loadPreviewCmd()
+
+	case key.Matches(msg, _downstackEditKeys.Down):
+		if m.cursor < len(m.items)-1 {
+			m.cursor++
+		}
+		return m, m.loadPreviewCmd()
+
+	case key.Matches(msg, _downstackEditKeys.MoveUp):
+		m.swap(m.cursor, m.cursor-1)
+		return m, m.loadPreviewCmd()
+
+	case key.Matches(msg, _downstackEditKeys.MoveDn):
+		m.swap(m.cursor, m.cursor+1)
+		return m, m.loadPreviewCmd()
+
+	case key.Matches(msg, _downstackEditKeys.Delete):
+		m.delete(m.cursor)
+		return m, m.loadPreviewCmd()
+
+	case key.Matches(msg, _downstackEditKeys.Insert):
+		return m, m.loadInsertCandidatesCmd()
+	}
+
+	return m, nil
+}
+
+// updateInserting handles key presses while the insert-branch picker
+// (opened by the "i" key) is showing.
+func (m *downstackEditModel) updateInserting(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	picker := m.inserting
+
+	switch {
+	case key.Matches(msg, _downstackEditKeys.Cancel):
+		m.inserting = nil
+		return m, nil
+
+	case key.Matches(msg, _downstackEditKeys.Up):
+		if picker.cursor > 0 {
+			picker.cursor--
+		}
+		return m, nil
+
+	case key.Matches(msg, _downstackEditKeys.Down):
+		if picker.cursor < len(picker.candidates)-1 {
+			picker.cursor++
+		}
+		return m, nil
+
+	case key.Matches(msg, _downstackEditKeys.Apply):
+		branch := picker.candidates[picker.cursor]
+		m.items = append(m.items[:m.cursor], append([]string{branch}, m.items[m.cursor:]...)...)
+		m.inserted[branch] = struct{}{}
+		m.inserting = nil
+		return m, m.loadPreviewCmd()
+	}
+
+	return m, nil
+}
+
+// swap exchanges the items at positions i and j and moves the cursor to j.
+// It does nothing if j is out of range.
+func (m *downstackEditModel) swap(i, j int) {
+	if j < 0 || j >= len(m.items) {
+		return
+	}
+	m.items[i], m.items[j] = m.items[j], m.items[i]
+	m.cursor = j
+}
+
+// delete removes the item at index i, equivalent to dropping its line
+// from the $EDITOR-based instruction file.
+func (m *downstackEditModel) delete(i int) {
+	if len(m.items) == 0 {
+		return
+	}
+	m.items = append(m.items[:i], m.items[i+1:]...)
+	if m.cursor >= len(m.items) {
+		m.cursor = max(len(m.items)-1, 0)
+	}
+}
+
+type insertCandidatesMsg struct{ candidates []string }
+
+// loadInsertCandidatesCmd fetches the branches available to insert: the
+// full tracked set, minus trunk and anything already in the edit list,
+// minus the branch below the cursor. The result opens the insert
+// picker; it does not insert anything on its own.
+func (m *downstackEditModel) loadInsertCandidatesCmd() tea.Cmd {
+	return func() tea.Msg {
+		all, err := m.svc.AllBranches(m.ctx)
+		if err != nil {
+			return previewLoadedMsg{text: fmt.Sprintf("list branches: %v", err)}
+		}
+
+		trunk := m.store.Trunk()
+		candidates := make([]string, 0, len(all))
+		for _, branch := range all {
+			if branch == trunk || contains(m.items, branch) {
+				continue
+			}
+			candidates = append(candidates, branch)
+		}
+
+		return insertCandidatesMsg{candidates: candidates}
+	}
+}
+
+type previewLoadedMsg struct{ text string }
+
+// loadPreviewCmd fetches the commits on the branch under the cursor so
+// they can be rendered in the side pane.
+func (m *downstackEditModel) loadPreviewCmd() tea.Cmd {
+	if len(m.items) == 0 {
+		return nil
+	}
+	branch := m.items[m.cursor]
+
+	return func() tea.Msg {
+		commits, err := m.svc.BranchCommits(m.ctx, branch)
+		if err != nil {
+			return previewLoadedMsg{text: fmt.Sprintf("commits on %s: %v", branch, err)}
+		}
+
+		var b strings.Builder
+		for _, c := range commits {
+			fmt.Fprintf(&b, "%s %s\n", c.ShortHash, c.Subject)
+		}
+		return previewLoadedMsg{text: b.String()}
+	}
+}
+
+func (m *downstackEditModel) View() string {
+	if m.inserting != nil {
+		return m.viewInserting()
+	}
+
+	var list strings.Builder
+	for i, branch := range m.items {
+		cursor := "  "
+		if i == m.cursor {
+			cursor = "> "
+		}
+
+		line := branch
+		if i == m.cursor {
+			line = _selectedItemStyle.Render(line)
+		}
+		fmt.Fprintf(&list, "%s%s\n", cursor, line)
+	}
+
+	help := _helpStyle.Render(
+		"↑/↓ move cursor · K/J move branch · d delete · i insert · enter apply · q abort",
+	)
+
+	return lipgloss.JoinHorizontal(lipgloss.Top,
+		list.String(),
+		_previewStyle.Render(m.viewport.View()),
+	) + "\n" + help
+}
+
+func (m *downstackEditModel) viewInserting() string {
+	var list strings.Builder
+	list.WriteString("Insert which branch?\n\n")
+	for i, branch := range m.inserting.candidates {
+		cursor := "  "
+		line := branch
+		if i == m.inserting.cursor {
+			cursor = "> "
+			line = _selectedItemStyle.Render(line)
+		}
+		fmt.Fprintf(&list, "%s%s\n", cursor, line)
+	}
+
+	help := _helpStyle.Render("↑/↓ choose branch · enter insert · esc cancel")
+
+	return list.String() + "\n" + help
+}
+
+func contains(ss []string, s string) bool {
+	for _, x := range ss {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDownstackEditModelSwap(t *testing.T) {
+	m := &downstackEditModel{items: []string{"a", "b", "c"}, cursor: 0}
+
+	m.swap(0, 1)
+	if want := []string{"b", "a", "c"}; !reflect.DeepEqual(m.items, want) {
+		t.Fatalf("items = %v, want %v", m.items, want)
+	}
+	if m.cursor != 1 {
+		t.Fatalf("cursor = %d, want 1", m.cursor)
+	}
+
+	// Swapping out of bounds is a no-op.
+	m.swap(m.cursor, len(m.items))
+	if want := []string{"b", "a", "c"}; !reflect.DeepEqual(m.items, want) {
+		t.Fatalf("items = %v, want %v after out-of-bounds swap", m.items, want)
+	}
+}
+
+func TestDownstackEditModelDelete(t *testing.T) {
+	tests := []struct {
+		name       string
+		items      []string
+		cursor     int
+		del        int
+		wantItems  []string
+		wantCursor int
+	}{
+		{
+			name:       "middle",
+			items:      []string{"a", "b", "c"},
+			cursor:     1,
+			del:        1,
+			wantItems:  []string{"a", "c"},
+			wantCursor: 1,
+		},
+		{
+			name:       "last",
+			items:      []string{"a", "b", "c"},
+			cursor:     2,
+			del:        2,
+			wantItems:  []string{"a", "b"},
+			wantCursor: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &downstackEditModel{items: tt.items, cursor: tt.cursor}
+			m.delete(tt.del)
+			if !reflect.DeepEqual(m.items, tt.wantItems) {
+				t.Errorf("items = %v, want %v", m.items, tt.wantItems)
+			}
+			if m.cursor != tt.wantCursor {
+				t.Errorf("cursor = %d, want %d", m.cursor, tt.wantCursor)
+			}
+		})
+	}
+}
+
+func TestContains(t *testing.T) {
+	if !contains([]string{"a", "b"}, "b") {
+		t.Error("expected contains to find existing element")
+	}
+	if contains([]string{"a", "b"}, "c") {
+		t.Error("expected contains to not find missing element")
+	}
+}
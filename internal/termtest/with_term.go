@@ -11,6 +11,7 @@ import (
 	"log"
 	"os"
 	"os/exec"
+	"regexp"
 	"strconv"
 	"strings"
 	"sync"
@@ -40,9 +41,24 @@ import (
 //     Wait up to 1 second for the given text to become visible on the screen.
 //     If [txt] is absent, wait until contents of the screen change
 //     compared to the last captured snapshot or last await empty.
+//   - awaitRe regex:
+//     Like await, but matches the snapshot against the given regular
+//     expression (as understood by the regexp package) instead of a
+//     literal substring. Useful for matching dynamic text such as
+//     commit SHAs or timestamps.
 //   - snapshot [name]:
 //     Take a picture of the screen as it is right now, and print it to stdout.
 //     If name is provided, the output will include that as a header.
+//   - snapshotStyled [name]:
+//     Like snapshot, but each run of cells sharing the same foreground
+//     color, background color, and attributes is wrapped as
+//     "{fg=...,bg=...,attrs}text{/}", so golden files can assert on
+//     styling (e.g. colors from charmbracelet/log) and not just text.
+//   - assertCell row col fg bg attrs:
+//     Fail if the cell at the given row and column does not have the
+//     given foreground color, background color, and attributes.
+//     fg/bg/attrs use the same names as in the snapshotStyled output;
+//     use "-" to skip checking a particular field.
 //   - feed txt:
 //     Feed the given string into the terminal.
 //     Go string-style escape codes are permitted without quotes.
@@ -168,6 +184,43 @@ func WithTerm() (exitCode int) {
 				lastSnapshot = last
 			}
 
+		case "awaitRe":
+			if len(rest) == 0 {
+				log.Printf("awaitRe: a regular expression is required")
+				exitCode = 1
+				continue
+			}
+
+			re, err := regexp.Compile(rest)
+			if err != nil {
+				log.Printf("awaitRe: invalid regexp %q: %v", rest, err)
+				exitCode = 1
+				continue
+			}
+
+			timeout := time.Second
+			start := time.Now()
+
+			var (
+				last    []byte
+				matched bool
+			)
+			for time.Since(start) < timeout {
+				last = emu.Snapshot()
+				if re.Match(last) {
+					matched = true
+					break
+				}
+				time.Sleep(50 * time.Millisecond)
+			}
+
+			if !matched {
+				log.Printf("awaitRe: no match for %q", rest)
+				exitCode = 1
+				log.Printf("###\n%s\n###", last)
+			}
+			lastSnapshot = last
+
 		case "snapshot":
 			lastSnapshot = emu.Snapshot()
 			if len(rest) > 0 {
@@ -177,6 +230,57 @@ func WithTerm() (exitCode int) {
 				log.Printf("error writing to stdout: %v", err)
 			}
 
+		case "snapshotStyled":
+			styled := emu.StyledSnapshot()
+			if len(rest) > 0 {
+				fmt.Printf("### %s ###\n", rest)
+			}
+			if _, err := os.Stdout.Write(styled); err != nil {
+				log.Printf("error writing to stdout: %v", err)
+			}
+
+		case "assertCell":
+			fields := strings.Fields(rest)
+			if len(fields) != 5 {
+				log.Printf("assertCell: expected 'row col fg bg attrs', got %q", rest)
+				exitCode = 1
+				continue
+			}
+
+			row, err := strconv.Atoi(fields[0])
+			if err != nil {
+				log.Printf("assertCell: invalid row %q: %v", fields[0], err)
+				exitCode = 1
+				continue
+			}
+			col, err := strconv.Atoi(fields[1])
+			if err != nil {
+				log.Printf("assertCell: invalid col %q: %v", fields[1], err)
+				exitCode = 1
+				continue
+			}
+
+			wantFG, wantBG, wantAttrs := fields[2], fields[3], fields[4]
+			gotFG, gotBG, gotAttrs, err := emu.CellStyle(row, col)
+			if err != nil {
+				log.Printf("assertCell: %v", err)
+				exitCode = 1
+				continue
+			}
+
+			if wantFG != "-" && wantFG != gotFG {
+				log.Printf("assertCell(%d,%d): fg = %s, want %s", row, col, gotFG, wantFG)
+				exitCode = 1
+			}
+			if wantBG != "-" && wantBG != gotBG {
+				log.Printf("assertCell(%d,%d): bg = %s, want %s", row, col, gotBG, wantBG)
+				exitCode = 1
+			}
+			if wantAttrs != "-" && wantAttrs != gotAttrs {
+				log.Printf("assertCell(%d,%d): attrs = %s, want %s", row, col, gotAttrs, wantAttrs)
+				exitCode = 1
+			}
+
 		case "feed":
 			s := strings.ReplaceAll(rest, `"`, `\"`)
 			s = `"` + s + `"`
@@ -274,3 +378,107 @@ func (m *terminalEmulator) Snapshot() []byte {
 
 	return append(bytes.TrimRight(buff.Bytes(), "\n"), '\n')
 }
+
+// StyledSnapshot is like Snapshot, but annotates runs of cells that
+// share the same style by wrapping them as "{fg=...,bg=...,attrs}text{/}".
+// This lets golden files assert on ANSI styling (e.g. colors applied by
+// charmbracelet/log) in addition to plain text.
+func (m *terminalEmulator) StyledSnapshot() []byte {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var buff bytes.Buffer
+	for y, row := range m.term.Content {
+		var rowBuff bytes.Buffer
+		var lastStyle string
+		open := false
+
+		for x := range row {
+			style := formatString(m.term.Format[y][x])
+			if style != lastStyle {
+				if open {
+					rowBuff.WriteString("{/}")
+				}
+				if style != "" {
+					fmt.Fprintf(&rowBuff, "{%s}", style)
+				}
+				lastStyle = style
+				open = style != ""
+			}
+			rowBuff.WriteRune(row[x])
+		}
+		if open {
+			rowBuff.WriteString("{/}")
+		}
+
+		buff.WriteString(strings.TrimRight(rowBuff.String(), " \t\n"))
+		buff.WriteRune('\n')
+	}
+
+	return append(bytes.TrimRight(buff.Bytes(), "\n"), '\n')
+}
+
+// CellStyle returns the foreground color, background color, and
+// attributes ("bold", "underline", ...; comma-separated, "" if none) of
+// the cell at the given row and column.
+func (m *terminalEmulator) CellStyle(row, col int) (fg, bg, attrs string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if row < 0 || row >= len(m.term.Format) || col < 0 || col >= len(m.term.Format[row]) {
+		return "", "", "", fmt.Errorf("cell (%d,%d) is out of bounds", row, col)
+	}
+
+	f := m.term.Format[row][col]
+	return colorName(f.Fg), colorName(f.Bg), attrsString(f), nil
+}
+
+// formatString renders a cell's format as "fg=...,bg=...,attrs" for use
+// in StyledSnapshot, omitting defaults.
+func formatString(f midterm.Format) string {
+	var parts []string
+	if fg := colorName(f.Fg); fg != "" {
+		parts = append(parts, "fg="+fg)
+	}
+	if bg := colorName(f.Bg); bg != "" {
+		parts = append(parts, "bg="+bg)
+	}
+	if attrs := attrsString(f); attrs != "" {
+		parts = append(parts, attrs)
+	}
+	return strings.Join(parts, ",")
+}
+
+// colorName renders a midterm color as a short name, or "" for the
+// terminal default.
+func colorName(c midterm.Color) string {
+	if c == midterm.DefaultColor {
+		return ""
+	}
+	return c.String()
+}
+
+// attrsString renders the boolean attributes of a format as a
+// comma-separated list, e.g. "bold,underline".
+func attrsString(f midterm.Format) string {
+	var attrs []string
+	if f.Bold {
+		attrs = append(attrs, "bold")
+	}
+	if f.Faint {
+		attrs = append(attrs, "faint")
+	}
+	if f.Italic {
+		attrs = append(attrs, "italic")
+	}
+	if f.Underline {
+		attrs = append(attrs, "underline")
+	}
+	if f.Blink {
+		attrs = append(attrs, "blink")
+	}
+	if f.Reverse {
+		attrs = append(attrs, "reverse")
+	}
+	return strings.Join(attrs, ",")
+}
@@ -0,0 +1,73 @@
+package termtest
+
+import (
+	"testing"
+
+	"github.com/vito/midterm"
+)
+
+func TestColorName(t *testing.T) {
+	tests := []struct {
+		name string
+		give midterm.Color
+		want string
+	}{
+		{name: "default", give: midterm.DefaultColor, want: ""},
+		{name: "named", give: midterm.ColorRed, want: midterm.ColorRed.String()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := colorName(tt.give); got != tt.want {
+				t.Errorf("colorName(%v) = %q, want %q", tt.give, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAttrsString(t *testing.T) {
+	tests := []struct {
+		name string
+		give midterm.Format
+		want string
+	}{
+		{name: "none", give: midterm.Format{}, want: ""},
+		{name: "bold", give: midterm.Format{Bold: true}, want: "bold"},
+		{
+			name: "bold and underline",
+			give: midterm.Format{Bold: true, Underline: true},
+			want: "bold,underline",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := attrsString(tt.give); got != tt.want {
+				t.Errorf("attrsString(%+v) = %q, want %q", tt.give, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatString(t *testing.T) {
+	tests := []struct {
+		name string
+		give midterm.Format
+		want string
+	}{
+		{name: "unstyled", give: midterm.Format{}, want: ""},
+		{
+			name: "fg and bold",
+			give: midterm.Format{Fg: midterm.ColorRed, Bold: true},
+			want: "fg=" + midterm.ColorRed.String() + ",bold",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatString(tt.give); got != tt.want {
+				t.Errorf("formatString(%+v) = %q, want %q", tt.give, got, tt.want)
+			}
+		})
+	}
+}
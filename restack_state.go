@@ -0,0 +1,245 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/charmbracelet/log"
+	"go.abhg.dev/gs/internal/git"
+	"go.abhg.dev/gs/internal/gs"
+)
+
+// restackState is the persisted, resumable state of an in-flight
+// restack-style operation. It's stored so that a restack interrupted by
+// a merge conflict can be resumed with --continue, or unwound with
+// --abort, using git-spice's normal rebase-continuation machinery
+// rather than a separate, parallel one.
+//
+// Only upstackRestackCmd writes this state. trunkCmd and
+// branchCheckoutCmd read it to refuse to run while a restack is in
+// progress. Downstack restack and branch restack do not use it.
+// Command records how to resume, so the state format does not name
+// a particular command.
+type restackState struct {
+	// Command is the full command line that should be used to resume
+	// this restack, e.g. "gs upstack restack --continue". Error
+	// messages reference this instead of hardcoding a command name.
+	Command string `json:"command"`
+
+	// Branch is the branch the command was run from,
+	// and the branch that should be checked out again once the
+	// restack completes or is aborted.
+	Branch string `json:"branch"`
+
+	// Branches is the ordered list of branches being restacked.
+	Branches []string `json:"branches"`
+
+	// Pos is the index into Branches of the branch whose rebase is
+	// currently in progress (or that needs to be retried after
+	// --continue).
+	Pos int `json:"pos"`
+
+	// Head is the commit Branch pointed at before the restack began.
+	Head string `json:"head"`
+
+	// BranchHeads records, for every branch in Branches, the commit it
+	// pointed at before the restack began. --abort uses this to reset
+	// any branch that was already successfully rebased before the
+	// conflict, in addition to aborting the git rebase left in
+	// progress on the branch that hit it.
+	BranchHeads map[string]string `json:"branchHeads"`
+}
+
+// loadRestackState returns the in-progress restack, if any.
+// It returns nil, nil if there is no restack in progress.
+func loadRestackState(ctx context.Context, store *gs.Store) (*restackState, error) {
+	var state restackState
+	if err := store.Load(ctx, _restackStateKey, &state); err != nil {
+		if errors.Is(err, gs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("load restack state: %w", err)
+	}
+	return &state, nil
+}
+
+func saveRestackState(ctx context.Context, store *gs.Store, state *restackState) error {
+	if err := store.Save(ctx, _restackStateKey, state); err != nil {
+		return fmt.Errorf("save restack state: %w", err)
+	}
+	return nil
+}
+
+func clearRestackState(ctx context.Context, store *gs.Store) error {
+	if err := store.Clear(ctx, _restackStateKey); err != nil {
+		return fmt.Errorf("clear restack state: %w", err)
+	}
+	return nil
+}
+
+const _restackStateKey = "restack-state.json"
+
+// requireNoRestackInProgress refuses to proceed if a restack is
+// currently in progress. trunkCmd and branchCheckoutCmd call this so
+// that the user doesn't accidentally navigate away mid-rebase and lose
+// track of where they were.
+func requireNoRestackInProgress(ctx context.Context, store *gs.Store) error {
+	state, err := loadRestackState(ctx, store)
+	if err != nil {
+		return err
+	}
+	if state != nil {
+		return fmt.Errorf("a restack is in progress: resolve the conflict and run %q, or run it with --abort in place of --continue", state.Command)
+	}
+	return nil
+}
+
+// conflictErrorf builds the error returned when a branch in the restack
+// loop hits a real conflict: it names the branch, wraps the underlying
+// git error, and tells the user how to resume using state.Command.
+func conflictErrorf(branch string, state *restackState, err error) error {
+	return fmt.Errorf("%v: %w\n\nresolve the conflict, stage the result, then run: %s", branch, err, state.Command)
+}
+
+// runRestack drives the restack loop starting at state.Pos, persisting
+// state as it goes so the operation can be resumed after a conflict.
+// Each iteration calls svc.Restack, which performs the rebase for one
+// branch; svc.Restack is only ever called for a branch whose rebase
+// hasn't started yet. A branch whose rebase is already in progress
+// (because a previous run of this loop stopped on it) must instead be
+// finished with continueRestack before this is called again.
+func runRestack(
+	ctx context.Context,
+	log *log.Logger,
+	repo *git.Repository,
+	svc *gs.Service,
+	store *gs.Store,
+	state *restackState,
+) error {
+	for ; state.Pos < len(state.Branches); state.Pos++ {
+		branch := state.Branches[state.Pos]
+
+		// Trunk never needs to be restacked.
+		if branch == store.Trunk() {
+			continue
+		}
+
+		// Record our place before this branch's rebase starts.
+		if err := saveRestackState(ctx, store, state); err != nil {
+			return err
+		}
+
+		res, err := svc.Restack(ctx, branch)
+		if err != nil {
+			switch {
+			case errors.Is(err, gs.ErrAlreadyRestacked):
+				if branch != state.Branch {
+					log.Infof("%v: branch does not need to be restacked.", branch)
+				}
+				continue
+			default:
+				// A real conflict: git-spice's rebase is left
+				// in progress on disk. Save our place and let
+				// the user resolve it before running --continue.
+				if saveErr := saveRestackState(ctx, store, state); saveErr != nil {
+					return errors.Join(err, saveErr)
+				}
+				return conflictErrorf(branch, state, err)
+			}
+		}
+
+		log.Infof("%v: restacked on %v", branch, res.Base)
+	}
+
+	return clearRestackState(ctx, store)
+}
+
+// continueRestack finishes the git rebase left in progress on
+// state.Branches[state.Pos] by the previous run of runRestack.
+//
+// It resumes through svc.ContinueRestack rather than calling
+// repo.RebaseContinue directly: svc.Restack records the branch's new
+// base in the store once its rebase completes, and svc.ContinueRestack
+// performs that same bookkeeping after finishing a rebase that was left
+// in progress, so a branch resumed via --continue ends up tracked
+// identically to one restacked in a single pass.
+//
+// If the continued rebase itself hits another conflict, state.Pos is
+// left unchanged and an error is returned asking the user to resolve
+// it and run --continue again. Otherwise, state.Pos is advanced past
+// the now-finished branch and the remaining branches are processed
+// normally via runRestack.
+func continueRestack(
+	ctx context.Context,
+	log *log.Logger,
+	repo *git.Repository,
+	svc *gs.Service,
+	store *gs.Store,
+	state *restackState,
+) error {
+	inProgress, err := repo.RebaseInProgress(ctx)
+	if err != nil {
+		return fmt.Errorf("check rebase status: %w", err)
+	}
+
+	if inProgress {
+		branch := state.Branches[state.Pos]
+		res, err := svc.ContinueRestack(ctx, branch)
+		if err != nil {
+			if saveErr := saveRestackState(ctx, store, state); saveErr != nil {
+				return errors.Join(err, saveErr)
+			}
+			return conflictErrorf(branch, state, err)
+		}
+		log.Infof("%v: restacked on %v", branch, res.Base)
+		state.Pos++
+	}
+
+	return runRestack(ctx, log, repo, svc, store, state)
+}
+
+// abortRestack unwinds an in-progress restack: it aborts the underlying
+// git rebase, if one is in progress, resets every branch recorded in
+// state.BranchHeads back to the commit it pointed at before the restack
+// began, clears the persisted state, and returns to the branch the
+// restack was started from.
+//
+// Resetting is necessary in addition to RebaseAbort: RebaseAbort only
+// unwinds the rebase left in progress on the branch that hit a
+// conflict. Branches earlier in state.Branches may already have been
+// rebased successfully before that conflict occurred, and would
+// otherwise be left rewritten even though the operation as a whole was
+// abandoned.
+func abortRestack(ctx context.Context, repo *git.Repository, store *gs.Store, state *restackState) error {
+	inProgress, err := repo.RebaseInProgress(ctx)
+	if err != nil {
+		return fmt.Errorf("check rebase status: %w", err)
+	}
+
+	if inProgress {
+		if err := repo.RebaseAbort(ctx); err != nil {
+			return fmt.Errorf("abort rebase: %w", err)
+		}
+	}
+
+	for _, branch := range state.Branches {
+		hash, ok := state.BranchHeads[branch]
+		if !ok {
+			continue
+		}
+		if err := repo.SetBranchHead(ctx, branch, hash); err != nil {
+			return fmt.Errorf("restore %v: %w", branch, err)
+		}
+	}
+
+	if err := clearRestackState(ctx, store); err != nil {
+		return err
+	}
+
+	if err := repo.Checkout(ctx, state.Branch); err != nil {
+		return fmt.Errorf("checkout branch %v: %w", state.Branch, err)
+	}
+
+	return nil
+}
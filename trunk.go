@@ -25,6 +25,10 @@ func (cmd *trunkCmd) Run(ctx context.Context, log *log.Logger, opts *globalOptio
 		return err
 	}
 
+	if err := requireNoRestackInProgress(ctx, store); err != nil {
+		return err
+	}
+
 	trunk := store.Trunk()
 	return (&branchCheckoutCmd{
 		checkoutOptions: cmd.checkoutOptions,
@@ -10,9 +10,12 @@ import (
 	"go.abhg.dev/gs/internal/gs"
 )
 
-type upstackRestackCmd struct{}
+type upstackRestackCmd struct {
+	Continue bool `help:"Continue an upstack restack that was interrupted by a conflict." xor:"mode"`
+	Abort    bool `help:"Abort an in-progress upstack restack and return to the original branch." xor:"mode"`
+}
 
-func (*upstackRestackCmd) Run(ctx context.Context, log *log.Logger) error {
+func (cmd *upstackRestackCmd) Run(ctx context.Context, log *log.Logger) error {
 	repo, err := git.Open(ctx, ".", git.OpenOptions{
 		Log: log,
 	})
@@ -25,47 +28,93 @@ func (*upstackRestackCmd) Run(ctx context.Context, log *log.Logger) error {
 		return err
 	}
 
+	svc := gs.NewService(repo, store, log)
+
+	state, err := loadRestackState(ctx, store)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case cmd.Abort:
+		if state == nil {
+			return errors.New("no upstack restack is in progress")
+		}
+		if err := abortRestack(ctx, repo, store, state); err != nil {
+			return err
+		}
+		log.Infof("aborted restack: checked out %v", state.Branch)
+		return nil
+
+	case cmd.Continue:
+		if state == nil {
+			return errors.New("no upstack restack is in progress")
+		}
+		if err := continueRestack(ctx, log, repo, svc, store, state); err != nil {
+			return err
+		}
+		return checkoutOriginal(ctx, repo, state)
+
+	default:
+		if state != nil {
+			return fmt.Errorf("a restack is in progress: resolve the conflict and run %q, or run it with --abort in place of --continue", state.Command)
+		}
+		return cmd.start(ctx, log, repo, svc, store)
+	}
+}
+
+func (cmd *upstackRestackCmd) start(
+	ctx context.Context,
+	log *log.Logger,
+	repo *git.Repository,
+	svc *gs.Service,
+	store *gs.Store,
+) error {
 	currentBranch, err := repo.CurrentBranch(ctx)
 	if err != nil {
 		return fmt.Errorf("get current branch: %w", err)
 	}
 
-	svc := gs.NewService(repo, store, log)
-
 	upstacks, err := svc.ListUpstack(ctx, currentBranch)
 	if err != nil {
 		return fmt.Errorf("get upstack branches: %w", err)
 	}
 
-loop:
-	for _, upstack := range upstacks {
-		// Trunk never needs to be restacked.
-		if upstack == store.Trunk() {
-			continue loop
-		}
+	head, err := repo.CommitHash(ctx, currentBranch)
+	if err != nil {
+		return fmt.Errorf("resolve %v: %w", currentBranch, err)
+	}
 
-		res, err := svc.Restack(ctx, upstack)
+	branchHeads := make(map[string]string, len(upstacks))
+	for _, branch := range upstacks {
+		hash, err := repo.CommitHash(ctx, branch)
 		if err != nil {
-			switch {
-			case errors.Is(err, gs.ErrAlreadyRestacked):
-				// Log the "does not need to be restacked" message
-				// only for branches that are not the current branch.
-				if upstack != currentBranch {
-					log.Infof("%v: branch does not need to be restacked.", upstack)
-				}
-				continue loop
-			default:
-				return fmt.Errorf("restack branch: %w", err)
-			}
+			return fmt.Errorf("resolve %v: %w", branch, err)
 		}
+		branchHeads[branch] = hash
+	}
 
-		log.Infof("%v: restacked on %v", upstack, res.Base)
+	state := &restackState{
+		Command:     "gs upstack restack --continue",
+		Branch:      currentBranch,
+		Branches:    upstacks,
+		Pos:         0,
+		Head:        head,
+		BranchHeads: branchHeads,
 	}
 
-	// On success, check out the original branch.
-	if err := repo.Checkout(ctx, currentBranch); err != nil {
-		return fmt.Errorf("checkout branch %v: %w", currentBranch, err)
+	if err := runRestack(ctx, log, repo, svc, store, state); err != nil {
+		return err
 	}
 
+	return checkoutOriginal(ctx, repo, state)
+}
+
+// checkoutOriginal returns to the branch a restack was started from,
+// once the restack loop has completed without a pending conflict.
+func checkoutOriginal(ctx context.Context, repo *git.Repository, state *restackState) error {
+	if err := repo.Checkout(ctx, state.Branch); err != nil {
+		return fmt.Errorf("checkout branch %v: %w", state.Branch, err)
+	}
 	return nil
 }